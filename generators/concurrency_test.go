@@ -0,0 +1,151 @@
+package generators
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yext/edward/generators/memfs"
+	"github.com/yext/edward/services"
+)
+
+// poolGenerator is a ConcurrentGenerator test double that records, across
+// all of its clones, how many directories were visited and the highest
+// number of VisitDir calls that were ever in flight at once. Clones share
+// their parent's bookkeeping directly, so no Mergeable implementation is
+// needed to fold a clone's results back in.
+type poolGenerator struct {
+	generatorBase
+	mu        *sync.Mutex
+	current   *int
+	maxActive *int
+	visited   *[]string
+}
+
+func newPoolGenerator() *poolGenerator {
+	return &poolGenerator{
+		mu:        &sync.Mutex{},
+		current:   new(int),
+		maxActive: new(int),
+		visited:   &[]string{},
+	}
+}
+
+func (g *poolGenerator) Name() string { return "pool" }
+
+func (g *poolGenerator) VisitDir(ctx context.Context, path string, fs FileSystem) (bool, error) {
+	g.mu.Lock()
+	*g.current++
+	if *g.current > *g.maxActive {
+		*g.maxActive = *g.current
+	}
+	*g.visited = append(*g.visited, path)
+	g.mu.Unlock()
+
+	runtime.Gosched()
+
+	g.mu.Lock()
+	*g.current--
+	g.mu.Unlock()
+	return false, nil
+}
+
+func (g *poolGenerator) Clone() Generator {
+	return &poolGenerator{mu: g.mu, current: g.current, maxActive: g.maxActive, visited: g.visited}
+}
+
+func TestGenerate_ConcurrentGeneratorVisitsEveryDirectory(t *testing.T) {
+	filesystem := memfs.New().Dir("a").Dir("b").Dir("c")
+	gen := newPoolGenerator()
+	gc := &GeneratorCollection{
+		Generators:  []Generator{gen},
+		Path:        ".",
+		FS:          filesystem,
+		Concurrency: 2,
+	}
+
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if len(*gen.visited) != 4 {
+		t.Fatalf("expected the root plus its 3 children to be visited, got %v", *gen.visited)
+	}
+	if *gen.maxActive > gc.Concurrency {
+		t.Fatalf("expected at most %d VisitDir calls in flight at once, saw %d", gc.Concurrency, *gen.maxActive)
+	}
+}
+
+// mergingServiceGenerator is a ConcurrentGenerator+Mergeable+ServiceGenerator
+// test double. Unlike poolGenerator, each clone accumulates its own
+// independent services slice, so the only way a service discovered in one
+// worker's branch reaches GeneratorCollection.Services() is through Merge.
+type mergingServiceGenerator struct {
+	generatorBase
+	services []*services.ServiceConfig
+}
+
+func (g *mergingServiceGenerator) Name() string { return "merging" }
+
+func (g *mergingServiceGenerator) VisitDir(ctx context.Context, path string, fs FileSystem) (bool, error) {
+	g.services = append(g.services, &services.ServiceConfig{Name: path})
+	return false, nil
+}
+
+func (g *mergingServiceGenerator) Services() []*services.ServiceConfig {
+	return g.services
+}
+
+func (g *mergingServiceGenerator) Clone() Generator {
+	return &mergingServiceGenerator{}
+}
+
+func (g *mergingServiceGenerator) Merge(clone Generator) {
+	g.services = append(g.services, clone.(*mergingServiceGenerator).services...)
+}
+
+func TestGenerate_MergesServicesFromEveryConcurrentBranch(t *testing.T) {
+	filesystem := memfs.New().Dir("a").Dir("b").Dir("c")
+	gen := &mergingServiceGenerator{}
+	gc := &GeneratorCollection{
+		Generators:  []Generator{gen},
+		Path:        ".",
+		FS:          filesystem,
+		Concurrency: 2,
+	}
+
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	var found []string
+	for _, service := range gc.Services() {
+		found = append(found, service.Name)
+	}
+	sort.Strings(found)
+
+	want := []string{".", "a", "b", "c"}
+	if strings.Join(found, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected services merged from every branch %v, got %v", want, found)
+	}
+}
+
+func TestGenerate_StopsOnContextCancellation(t *testing.T) {
+	filesystem := memfs.New().Dir("a")
+	gen := newPoolGenerator()
+	gc := &GeneratorCollection{
+		Generators: []Generator{gen},
+		Path:       ".",
+		FS:         filesystem,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := gc.Generate(ctx); err == nil {
+		t.Fatal("expected Generate to return an error for an already-cancelled context")
+	}
+}
@@ -0,0 +1,32 @@
+// Package fs declares the filesystem abstraction shared by the generators
+// package and its FileSystem implementations (osfs, memfs). It is split out
+// from generators itself so that those implementations can depend on the
+// interface without creating an import cycle back into generators.
+package fs
+
+import (
+	"io"
+	"os"
+)
+
+// FileSystem abstracts the directory scanning operations used by the
+// generator walk. This lets GeneratorCollection be pointed at something
+// other than the local OS filesystem -- an in-memory tree in tests, a zip
+// bundle, a remote checkout -- without touching the traversal or generator
+// logic.
+//
+// The default implementation, osfs.New, wraps the OS filesystem rooted at
+// a given path.
+type FileSystem interface {
+	ReadDir(path string) ([]os.FileInfo, error)
+	Open(path string) (File, error)
+	Stat(path string) (os.FileInfo, error)
+}
+
+// File is the subset of *os.File that generators need in order to read
+// manifest files (package.json, Procfile, Dockerfile, ...) through a
+// FileSystem.
+type File interface {
+	io.Reader
+	io.Closer
+}
@@ -1,25 +1,62 @@
 package generators
 
 import (
-	"io/ioutil"
-	"os"
+	"context"
 	"path/filepath"
 	"sort"
+	"sync"
 
 	"github.com/pkg/errors"
-	"github.com/sabhiram/go-git-ignore"
+	"github.com/yext/edward/generators/fs"
+	"github.com/yext/edward/generators/osfs"
 	"github.com/yext/edward/services"
 )
 
+// FileSystem and File are aliases for the types of the same name in
+// generators/fs, kept here so generator implementations can refer to
+// generators.FileSystem without an extra import.
+type (
+	FileSystem = fs.FileSystem
+	File       = fs.File
+)
+
 type Generator interface {
 	Name() string
 	StartWalk(basePath string)
 	StopWalk()
-	VisitDir(path string) (bool, error)
+	// VisitDir inspects the directory at path, reading any manifest files it
+	// needs through filesystem, and returns true if this generator claimed
+	// the directory (causing the walk to skip the remaining generators for it
+	// and its subdirectories). Serial and ConcurrentGenerator generators are
+	// walked separately, so a claim only skips generators of the same kind.
+	// Implementations should check ctx.Done() on long-running work and
+	// return its error.
+	VisitDir(ctx context.Context, path string, filesystem FileSystem) (bool, error)
 	Err() error
 	SetErr(err error)
 }
 
+// ConcurrentGenerator is implemented by generators that are safe to run
+// against multiple directory branches at once. GeneratorCollection.Generate
+// clones them via Clone once for each top-level subdirectory it hands to its
+// worker pool; generators that don't
+// implement this interface are walked serially instead.
+type ConcurrentGenerator interface {
+	Generator
+
+	// Clone returns an independent copy of the generator, seeded from the
+	// same configuration, for a single worker to accumulate results into.
+	Clone() Generator
+}
+
+// Mergeable is implemented by ConcurrentGenerator clones that accumulate
+// state across a walk (e.g. a growing Services() slice). After a worker
+// finishes its branch, GeneratorCollection folds the clone's results back
+// into the original generator via Merge.
+type Mergeable interface {
+	Merge(clone Generator)
+}
+
 type ServiceGenerator interface {
 	Services() []*services.ServiceConfig
 }
@@ -56,20 +93,20 @@ type directory struct {
 	Path     string
 	Parent   *directory
 	children []*directory
-	ignores  *ignore.GitIgnore
+	ignores  *ignoreLayer
 }
 
-func NewDirectory(path string, parent *directory) (*directory, error) {
-	if parent != nil && parent.Ignores() != nil && parent.Ignores().MatchesPath(path) {
+func NewDirectory(path string, parent *directory, filesystem FileSystem, respectGitignore bool) (*directory, error) {
+	if parent != nil && parent.IgnoreStack().Matches(path) {
 		return nil, nil
 	}
 
-	ignores, err := loadIgnores(path, nil)
+	layer, err := loadIgnoreLayer(path, filesystem, respectGitignore)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	files, err := ioutil.ReadDir(path)
+	files, err := filesystem.ReadDir(path)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -77,15 +114,18 @@ func NewDirectory(path string, parent *directory) (*directory, error) {
 	d := &directory{
 		Path:    path,
 		Parent:  parent,
-		ignores: ignores,
+		ignores: layer,
 	}
 
 	for _, file := range files {
 		if file.IsDir() {
-			child, err := NewDirectory(filepath.Join(path, file.Name()), d)
+			child, err := NewDirectory(filepath.Join(path, file.Name()), d, filesystem, respectGitignore)
 			if err != nil {
 				return nil, errors.WithStack(err)
 			}
+			if child == nil {
+				continue
+			}
 			d.children = append(d.children, child)
 		}
 	}
@@ -93,41 +133,37 @@ func NewDirectory(path string, parent *directory) (*directory, error) {
 	return d, nil
 }
 
-// Ignores returns the .edwardignore config for this directory or any of its
-// ancestor directories.
-func (d *directory) Ignores() *ignore.GitIgnore {
-	if d.ignores != nil {
-		return d.ignores
-	}
-
+// IgnoreStack returns the .edwardignore (and, if enabled, .gitignore)
+// layers in effect for this directory, from the scan root down to here.
+func (d *directory) IgnoreStack() ignoreStack {
+	var stack ignoreStack
 	if d.Parent != nil {
-		return d.Parent.Ignores()
+		stack = d.Parent.IgnoreStack()
 	}
-	return nil
+	if d.ignores != nil {
+		stack = append(stack, *d.ignores)
+	}
+	return stack
 }
 
-func (d *directory) Generate(generators []Generator) error {
+func (d *directory) Generate(ctx context.Context, generators []Generator, filesystem FileSystem, tracker *walkTracker) error {
 	if d == nil || len(generators) == 0 {
 		return nil
 	}
+	if err := ctx.Err(); err != nil {
+		return errors.WithStack(err)
+	}
 
-	var childGenerators []Generator
-	for _, generator := range generators {
-		found, err := generator.VisitDir(d.Path)
-		if err != nil && err != filepath.SkipDir {
-			return errors.WithStack(err)
-		}
-		if err != filepath.SkipDir {
-			childGenerators = append(childGenerators, generator)
-		}
-		if found {
-			break
-		}
+	childGenerators, found, err := d.visit(ctx, generators, filesystem, tracker)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if found {
+		return nil
 	}
 
 	for _, child := range d.children {
-		err := child.Generate(childGenerators)
-		if err != nil {
+		if err := child.Generate(ctx, childGenerators, filesystem, tracker); err != nil {
 			return errors.WithStack(err)
 		}
 	}
@@ -135,55 +171,277 @@ func (d *directory) Generate(generators []Generator) error {
 	return nil
 }
 
-func loadIgnores(path string, currentIgnores *ignore.GitIgnore) (*ignore.GitIgnore, error) {
-	ignoreFile := filepath.Join(path, ".edwardignore")
-	if _, err := os.Stat(ignoreFile); err != nil {
-		if os.IsNotExist(err) {
-			return currentIgnores, nil
+// visit runs generators against d without recursing into its children. It
+// returns the subset of generators that should continue on to d's
+// children (those that didn't return filepath.SkipDir) and whether one of
+// them claimed d outright.
+//
+// Before calling VisitDir on a Resumable generator, it checks tracker for
+// a fingerprint recorded for d on a previous walk; if d's contents still
+// match, the generator is replayed (see Resumable) instead of being
+// visited again. Generators that don't implement Resumable have no
+// replay path and are always visited.
+func (d *directory) visit(ctx context.Context, generators []Generator, filesystem FileSystem, tracker *walkTracker) ([]Generator, bool, error) {
+	fingerprint, fingerprintErr := tracker.fingerprint(d.Path)
+
+	var childGenerators []Generator
+	for _, generator := range generators {
+		if resumable, ok := generator.(Resumable); ok && fingerprintErr == nil {
+			if prior, ok := tracker.prior(generator.Name(), d.Path, fingerprint); ok {
+				if err := resumable.Replay(d.Path, prior); err != nil {
+					return nil, false, errors.WithStack(err)
+				}
+				tracker.record(generator.Name(), d.Path, prior)
+				childGenerators = append(childGenerators, generator)
+				continue
+			}
 		}
-		return currentIgnores, errors.WithStack(err)
-	}
 
-	ignores, err := ignore.CompileIgnoreFile(ignoreFile)
-	return ignores, errors.WithStack(err)
+		found, err := generator.VisitDir(ctx, d.Path, filesystem)
+		state := fingerprint
+		if err != nil && err != filepath.SkipDir {
+			state.Err = err.Error()
+			tracker.record(generator.Name(), d.Path, state)
+			return nil, false, errors.WithStack(err)
+		}
+		tracker.record(generator.Name(), d.Path, state)
+		if err != filepath.SkipDir {
+			childGenerators = append(childGenerators, generator)
+		}
+		if found {
+			return childGenerators, true, nil
+		}
+	}
+	return childGenerators, false, nil
 }
 
+// GeneratorCollection walks a directory tree, invoking a set of Generators
+// on each directory to discover services, groups and imports.
+//
+// FS controls how the walk reads the tree; if nil, it defaults to the local
+// OS filesystem rooted at Path (see osfs.New).
+//
+// Concurrency bounds how many directory branches are walked at once for
+// generators that implement ConcurrentGenerator; 0 or 1 walks serially.
+// Generators that don't implement ConcurrentGenerator always run on a
+// single serial worker, since their state can't safely be shared across
+// goroutines.
+//
+// RespectGitignore makes .gitignore files compose with .edwardignore when
+// deciding which directories to skip; it is off by default so existing
+// trees aren't affected by gitignore rules edward wasn't told about.
+//
+// Registry (DefaultRegistry, if left nil) supplies additional generators to
+// run alongside (or instead of) Generators, filtered against Context -- or the constraints
+// of the running process, if Context is left unset -- before the walk
+// starts. This is how third-party generators registered via Registry.
+// Register get picked up without edward core needing to know about them.
+//
+// StateStore, if set, persists a fingerprint of each directory visited so
+// that a later Generate call can replay, rather than re-visit, a Resumable
+// generator on a directory whose own fingerprint hasn't changed since. The
+// whole tree is still walked and every directory fingerprinted; unchanged
+// subtrees are not skipped as a whole. Force skips loading the stored state,
+// so every directory is visited again; the new state is still saved.
 type GeneratorCollection struct {
-	Generators []Generator
-	Path       string
-	Targets    []string
+	Generators       []Generator
+	Path             string
+	Targets          []string
+	FS               FileSystem
+	Concurrency      int
+	RespectGitignore bool
+	Registry         *Registry
+	Context          BuildConstraints
+	StateStore       StateStore
+	Force            bool
+
+	lastState *WalkState
 }
 
-func (g *GeneratorCollection) Generate() error {
-	if info, err := os.Stat(g.Path); err != nil || !info.IsDir() {
+func (g *GeneratorCollection) Generate(ctx context.Context) error {
+	filesystem := g.FS
+	if filesystem == nil {
+		filesystem = osfs.New(g.Path)
+	}
+
+	generators := g.generators()
+
+	if info, err := filesystem.Stat(g.Path); err != nil || !info.IsDir() {
 		if err != nil {
 			return errors.WithStack(err)
 		}
 		return errors.New(g.Path + " is not a directory")
 	}
 
-	dir, err := NewDirectory(g.Path, nil)
+	dir, err := NewDirectory(g.Path, nil, filesystem, g.RespectGitignore)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	for _, generator := range g.Generators {
+	var previousState *WalkState
+	if g.StateStore != nil && !g.Force {
+		previousState, err = g.StateStore.Load()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	tracker := newWalkTracker(filesystem, previousState)
+
+	for _, generator := range generators {
 		generator.StartWalk(g.Path)
 	}
 	defer func() {
-		for _, generator := range g.Generators {
+		for _, generator := range generators {
 			generator.StopWalk()
 		}
 	}()
 
-	return errors.WithStack(dir.Generate(g.Generators))
+	var concurrentSafe, serial []Generator
+	for _, generator := range generators {
+		if _, ok := generator.(ConcurrentGenerator); ok {
+			concurrentSafe = append(concurrentSafe, generator)
+		} else {
+			serial = append(serial, generator)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	if len(serial) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := dir.Generate(ctx, serial, filesystem, tracker); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	if len(concurrentSafe) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := g.generateConcurrent(ctx, dir, concurrentSafe, filesystem, tracker); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	g.lastState = tracker.current
+
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if g.StateStore != nil {
+		if err := g.StateStore.Save(tracker.current); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// concurrency returns the configured worker pool size, defaulting to a
+// single serial worker when unset.
+func (g *GeneratorCollection) concurrency() int {
+	if g.Concurrency < 1 {
+		return 1
+	}
+	return g.Concurrency
+}
+
+// generateConcurrent walks dir's subtree for generators, fanning its
+// top-level subdirectories out across a bounded worker pool. Each branch
+// gets its own clones of the generators via ConcurrentGenerator.Clone so it
+// can be walked without racing the others, and their results are folded
+// back into the originals via Mergeable once the branch completes.
+func (g *GeneratorCollection) generateConcurrent(ctx context.Context, dir *directory, generators []Generator, filesystem FileSystem, tracker *walkTracker) error {
+	childGenerators, found, err := dir.visit(ctx, generators, filesystem, tracker)
+	if err != nil || found || len(dir.children) == 0 {
+		return err
+	}
+
+	sem := make(chan struct{}, g.concurrency())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for _, child := range dir.children {
+		child := child
+		clones := cloneGenerators(childGenerators)
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := child.Generate(ctx, clones, filesystem, tracker); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			mergeGenerators(childGenerators, clones)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func cloneGenerators(generators []Generator) []Generator {
+	clones := make([]Generator, len(generators))
+	for i, generator := range generators {
+		clones[i] = generator.(ConcurrentGenerator).Clone()
+	}
+	return clones
+}
+
+func mergeGenerators(originals, clones []Generator) {
+	for i, original := range originals {
+		if mergeable, ok := original.(Mergeable); ok {
+			mergeable.Merge(clones[i])
+		}
+	}
+}
+
+// generators returns every generator this collection runs: the
+// statically-configured Generators plus whatever the Registry (DefaultRegistry,
+// if Registry is left nil) contributes for the current BuildConstraints. It's
+// recomputed fresh on every call rather than cached on g.Generators, so
+// calling Generate more than once on the same collection doesn't
+// double-register the Registry's generators.
+func (g *GeneratorCollection) generators() []Generator {
+	registry := g.Registry
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+
+	buildContext := g.Context
+	if buildContext.isZero() {
+		buildContext = CurrentBuildConstraints()
+	}
+
+	all := append([]Generator{}, g.Generators...)
+	return append(all, registry.Build(buildContext)...)
 }
 
 func (g *GeneratorCollection) Services() []*services.ServiceConfig {
 	var outServices []*services.ServiceConfig
 	var serviceToGenerator = make(map[string]string)
 
-	for _, generator := range g.Generators {
+	for _, generator := range g.generators() {
 		if serviceGenerator, ok := generator.(ServiceGenerator); ok && generator.Err() == nil {
 			found := serviceGenerator.Services()
 			for _, service := range found {
@@ -217,7 +475,7 @@ func (g *GeneratorCollection) Groups() []*services.ServiceGroupConfig {
 	var outGroups []*services.ServiceGroupConfig
 	var groupToGenerator = make(map[string]string)
 
-	for _, generator := range g.Generators {
+	for _, generator := range g.generators() {
 		if groupGenerator, ok := generator.(GroupGenerator); ok && generator.Err() == nil {
 			found := groupGenerator.Groups()
 			for _, group := range found {
@@ -249,7 +507,7 @@ func (g *GeneratorCollection) Groups() []*services.ServiceGroupConfig {
 
 func (g *GeneratorCollection) Imports() []string {
 	var outImports []string
-	for _, generator := range g.Generators {
+	for _, generator := range g.generators() {
 		if importGenerator, ok := generator.(ImportGenerator); ok && generator.Err() == nil {
 			outImports = append(outImports, importGenerator.Imports()...)
 		}
@@ -257,6 +515,17 @@ func (g *GeneratorCollection) Imports() []string {
 	return outImports
 }
 
+// ListGenerators returns the names of every generator registered in g's
+// Registry (DefaultRegistry, if Registry is left nil), regardless of
+// platform.
+func (g *GeneratorCollection) ListGenerators() []string {
+	registry := g.Registry
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return registry.ListGenerators()
+}
+
 type ByGroupName []*services.ServiceGroupConfig
 
 func (s ByGroupName) Len() int {
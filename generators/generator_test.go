@@ -0,0 +1,45 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/yext/edward/generators/memfs"
+)
+
+func TestNewDirectory_NoIgnoreFiles(t *testing.T) {
+	fs := memfs.New().
+		Dir("a").
+		Dir("a/b").
+		WriteFile("a/main.go", []byte("package main"))
+
+	dir, err := NewDirectory(".", nil, fs, false)
+	if err != nil {
+		t.Fatalf("NewDirectory returned an error for a tree with no .edwardignore files: %v", err)
+	}
+	if dir == nil {
+		t.Fatal("NewDirectory returned a nil directory for an existing path")
+	}
+	if len(dir.children) != 1 || dir.children[0].Path != "a" {
+		t.Fatalf("expected a single child directory %q, got %+v", "a", dir.children)
+	}
+}
+
+func TestNewDirectory_EdwardignoreExcludesChild(t *testing.T) {
+	fs := memfs.New().
+		Dir("a").
+		Dir("a/vendor").
+		WriteFile("a/.edwardignore", []byte("vendor\n"))
+
+	dir, err := NewDirectory(".", nil, fs, false)
+	if err != nil {
+		t.Fatalf("NewDirectory returned an unexpected error: %v", err)
+	}
+
+	if len(dir.children) != 1 {
+		t.Fatalf("expected one child, got %d", len(dir.children))
+	}
+	a := dir.children[0]
+	if len(a.children) != 0 {
+		t.Fatalf("expected vendor/ to be excluded by .edwardignore, got children %+v", a.children)
+	}
+}
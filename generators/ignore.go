@@ -0,0 +1,150 @@
+package generators
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+)
+
+// ignorePattern is a single line from an .edwardignore/.gitignore file,
+// with its leading "!" (negation) already stripped off.
+type ignorePattern struct {
+	pattern string
+	negate  bool
+}
+
+// ignoreLayer is the set of ignore patterns found directly in one
+// directory, rooted at basePath.
+type ignoreLayer struct {
+	basePath string
+	patterns []ignorePattern
+}
+
+// ignoreStack is every ignoreLayer in effect for a directory, ordered from
+// the scan root down to that directory.
+type ignoreStack []ignoreLayer
+
+// Matches reports whether path is ignored by the stack, evaluating layers
+// top-down so that a deeper layer's patterns -- including negations --
+// take precedence over a shallower one's, mirroring gitignore semantics.
+func (s ignoreStack) Matches(path string) bool {
+	ignored := false
+	for _, layer := range s {
+		rel, err := filepath.Rel(layer.basePath, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, p := range layer.patterns {
+			if matchIgnorePattern(p.pattern, rel) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// matchIgnorePattern matches a single gitignore-style pattern, which may
+// use doublestar's "**" to match across directory separators, against rel,
+// a slash-separated path relative to the layer's base directory.
+//
+// Whether a pattern is anchored to the layer's own directory (as opposed to
+// matching at any depth) is decided from the pattern as written -- a
+// leading "/", or a "/" anywhere other than a single trailing one, anchors
+// it -- before any rewriting for doublestar happens below.
+func matchIgnorePattern(pattern, rel string) bool {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		anchored = true
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern = strings.TrimSuffix(pattern, "/") + "/**"
+	}
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+
+	ok, _ := doublestar.Match(pattern, rel)
+	return ok
+}
+
+// parseIgnoreLines turns the lines of an ignore file into an ignoreLayer
+// rooted at basePath, skipping blank lines and "#" comments.
+func parseIgnoreLines(basePath string, lines []string) ignoreLayer {
+	layer := ignoreLayer{basePath: basePath}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		if negate {
+			line = strings.TrimPrefix(line, "!")
+		}
+
+		layer.patterns = append(layer.patterns, ignorePattern{pattern: line, negate: negate})
+	}
+	return layer
+}
+
+// loadIgnoreLayer reads path's .edwardignore, and -- if respectGitignore is
+// set -- its .gitignore, into a single layer rooted at path. A directory
+// with neither file returns a nil layer.
+func loadIgnoreLayer(path string, filesystem FileSystem, respectGitignore bool) (*ignoreLayer, error) {
+	var lines []string
+
+	edwardLines, err := readIgnoreFile(path, ".edwardignore", filesystem)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lines = append(lines, edwardLines...)
+
+	if respectGitignore {
+		gitLines, err := readIgnoreFile(path, ".gitignore", filesystem)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		lines = append(lines, gitLines...)
+	}
+
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	layer := parseIgnoreLines(path, lines)
+	return &layer, nil
+}
+
+func readIgnoreFile(path, name string, filesystem FileSystem) ([]string, error) {
+	ignoreFile := filepath.Join(path, name)
+	if _, err := filesystem.Stat(ignoreFile); err != nil {
+		// FileSystem implementations (e.g. osfs) wrap the underlying error
+		// with errors.WithStack, which os.IsNotExist can't see through, so
+		// unwrap to the root cause before checking.
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	file, err := filesystem.Open(ignoreFile)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return strings.Split(string(contents), "\n"), nil
+}
@@ -0,0 +1,61 @@
+package generators
+
+import "testing"
+
+func TestIgnoreStack_DeeperLayerOverridesShallower(t *testing.T) {
+	stack := ignoreStack{
+		parseIgnoreLines(".", []string{"vendor"}),
+		parseIgnoreLines("a", []string{"!vendor"}),
+	}
+
+	if stack.Matches("a/vendor") {
+		t.Fatal("expected a deeper layer's negation to override a shallower layer's ignore")
+	}
+	if !stack.Matches("b/vendor") {
+		t.Fatal("expected the shallower layer's pattern to still apply outside the deeper layer")
+	}
+}
+
+func TestIgnoreStack_DoublestarCrossesDirectories(t *testing.T) {
+	stack := ignoreStack{
+		parseIgnoreLines(".", []string{"**/*.generated.go"}),
+	}
+
+	if !stack.Matches("a/b/c/x.generated.go") {
+		t.Fatal("expected ** to match across multiple directory levels")
+	}
+	if stack.Matches("a/b/c/x.go") {
+		t.Fatal("did not expect a non-matching file to be ignored")
+	}
+}
+
+func TestIgnoreStack_UnrelatedPathIsUnaffected(t *testing.T) {
+	stack := ignoreStack{
+		parseIgnoreLines("a", []string{"vendor"}),
+	}
+
+	if stack.Matches("b/vendor") {
+		t.Fatal("did not expect a layer rooted at a to affect a sibling directory b")
+	}
+}
+
+func TestMatchIgnorePattern_TrailingSlashMatchesAnyDepth(t *testing.T) {
+	if !matchIgnorePattern("vendor/", "vendor") {
+		t.Fatal("expected a trailing-slash pattern to match the directory itself")
+	}
+	if !matchIgnorePattern("vendor/", "a/vendor") {
+		t.Fatal("expected a trailing-slash pattern to still match at any depth")
+	}
+	if !matchIgnorePattern("vendor/", "a/vendor/b") {
+		t.Fatal("expected a trailing-slash pattern to match contents of the matched directory")
+	}
+}
+
+func TestMatchIgnorePattern_LeadingSlashAnchorsToLayerRoot(t *testing.T) {
+	if !matchIgnorePattern("/vendor", "vendor") {
+		t.Fatal("expected a leading-slash pattern to match at the layer's own root")
+	}
+	if matchIgnorePattern("/vendor", "a/vendor") {
+		t.Fatal("expected a leading-slash pattern not to match a nested directory")
+	}
+}
@@ -0,0 +1,116 @@
+// Package memfs provides an in-memory generators.FileSystem, so generator
+// tests can describe a directory tree as data instead of writing it to
+// disk.
+package memfs
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	genfs "github.com/yext/edward/generators/fs"
+)
+
+// FS is an in-memory generators.FileSystem. Create one with New, which
+// returns an empty filesystem containing only the root directory "."; the
+// zero value has no maps and is not usable. Use Dir and WriteFile to
+// populate it before handing it to a GeneratorCollection.
+type FS struct {
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// New returns an empty FS.
+func New() *FS {
+	return &FS{
+		files: make(map[string][]byte),
+		dirs:  map[string]bool{".": true},
+	}
+}
+
+// Dir marks path as an existing directory, creating any missing ancestors.
+func (f *FS) Dir(p string) *FS {
+	p = path.Clean(p)
+	for p != "." && p != "/" {
+		f.dirs[p] = true
+		p = path.Dir(p)
+	}
+	f.dirs["."] = true
+	return f
+}
+
+// WriteFile adds a file at path with the given contents, creating any
+// missing parent directories.
+func (f *FS) WriteFile(p string, contents []byte) *FS {
+	p = path.Clean(p)
+	f.Dir(path.Dir(p))
+	f.files[p] = contents
+	return f
+}
+
+func (f *FS) ReadDir(p string) ([]os.FileInfo, error) {
+	p = path.Clean(p)
+	if !f.dirs[p] {
+		return nil, &os.PathError{Op: "open", Path: p, Err: os.ErrNotExist}
+	}
+
+	seen := make(map[string]os.FileInfo)
+	for dir := range f.dirs {
+		if dir != "." && path.Dir(dir) == p {
+			seen[dir] = fileInfo{name: path.Base(dir), isDir: true}
+		}
+	}
+	for file := range f.files {
+		if path.Dir(file) == p {
+			seen[file] = fileInfo{name: path.Base(file), size: int64(len(f.files[file]))}
+		}
+	}
+
+	var infos []os.FileInfo
+	for _, info := range seen {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+func (f *FS) Open(p string) (genfs.File, error) {
+	p = path.Clean(p)
+	contents, ok := f.files[p]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: p, Err: os.ErrNotExist}
+	}
+	return &nopCloserReader{Reader: bytes.NewReader(contents)}, nil
+}
+
+func (f *FS) Stat(p string) (os.FileInfo, error) {
+	p = path.Clean(p)
+	if f.dirs[p] {
+		return fileInfo{name: path.Base(p), isDir: true}, nil
+	}
+	if contents, ok := f.files[p]; ok {
+		return fileInfo{name: path.Base(p), size: int64(len(contents))}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: p, Err: os.ErrNotExist}
+}
+
+type nopCloserReader struct {
+	*bytes.Reader
+}
+
+func (n *nopCloserReader) Close() error { return nil }
+
+type fileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi fileInfo) Name() string       { return fi.name }
+func (fi fileInfo) Size() int64        { return fi.size }
+func (fi fileInfo) Mode() os.FileMode  { return 0644 }
+func (fi fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi fileInfo) IsDir() bool        { return fi.isDir }
+func (fi fileInfo) Sys() interface{}   { return nil }
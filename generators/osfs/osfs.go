@@ -0,0 +1,38 @@
+// Package osfs provides the default generators.FileSystem implementation,
+// backed by the local OS filesystem.
+package osfs
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/yext/edward/generators/fs"
+)
+
+// FS is a generators.FileSystem rooted at the local OS filesystem. Paths
+// passed to its methods may be absolute or relative to the process's
+// working directory; Root is informational only and is not prepended.
+type FS struct {
+	Root string
+}
+
+// New returns an FS rooted at root.
+func New(root string) *FS {
+	return &FS{Root: root}
+}
+
+func (f *FS) ReadDir(path string) ([]os.FileInfo, error) {
+	files, err := ioutil.ReadDir(path)
+	return files, errors.WithStack(err)
+}
+
+func (f *FS) Open(path string) (fs.File, error) {
+	file, err := os.Open(path)
+	return file, errors.WithStack(err)
+}
+
+func (f *FS) Stat(path string) (os.FileInfo, error) {
+	info, err := os.Stat(path)
+	return info, errors.WithStack(err)
+}
@@ -0,0 +1,121 @@
+package generators
+
+import (
+	"go/build"
+	"runtime"
+	"sort"
+)
+
+// BuildConstraints describes the platform a generator is willing to run
+// on, mirroring the parts of go/build.Context that matter for deciding
+// whether a generator's toolchain is even present. A zero-valued field is
+// treated as "any" for that dimension, so a generator only needs to set
+// the fields it actually cares about.
+type BuildConstraints struct {
+	GOOS       string
+	GOARCH     string
+	CgoEnabled bool
+	Tags       []string
+}
+
+// isZero reports whether c constrains nothing, i.e. hasn't been set.
+func (c BuildConstraints) isZero() bool {
+	return c.GOOS == "" && c.GOARCH == "" && !c.CgoEnabled && len(c.Tags) == 0
+}
+
+// Matches reports whether running is an acceptable environment for a
+// generator registered with constraints c.
+func (c BuildConstraints) Matches(running BuildConstraints) bool {
+	if c.GOOS != "" && c.GOOS != running.GOOS {
+		return false
+	}
+	if c.GOARCH != "" && c.GOARCH != running.GOARCH {
+		return false
+	}
+	if c.CgoEnabled && !running.CgoEnabled {
+		return false
+	}
+	for _, tag := range c.Tags {
+		if !hasTag(running.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentBuildConstraints returns the BuildConstraints describing the
+// process edward is running in, as reported by go/build.Default and
+// runtime.GOOS/GOARCH.
+func CurrentBuildConstraints() BuildConstraints {
+	return BuildConstraints{
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		CgoEnabled: build.Default.CgoEnabled,
+		Tags:       append([]string{}, build.Default.BuildTags...),
+	}
+}
+
+type registryEntry struct {
+	name        string
+	factory     func() Generator
+	constraints BuildConstraints
+}
+
+// Registry collects generator factories along with the platforms each one
+// applies to. Third-party packages can contribute a generator by calling
+// Register on DefaultRegistry from an init() function, without edward core
+// needing to know about them.
+type Registry struct {
+	entries []registryEntry
+}
+
+// DefaultRegistry is the Registry edward's built-in generators register
+// themselves into, and the one GeneratorCollection uses when its own
+// Registry field is left nil.
+var DefaultRegistry = &Registry{}
+
+// Register adds a generator factory under name, restricted to the
+// platforms described by constraints. Registering a name that already
+// exists replaces the previous entry.
+func (r *Registry) Register(name string, factory func() Generator, constraints BuildConstraints) {
+	for i, entry := range r.entries {
+		if entry.name == name {
+			r.entries[i] = registryEntry{name: name, factory: factory, constraints: constraints}
+			return
+		}
+	}
+	r.entries = append(r.entries, registryEntry{name: name, factory: factory, constraints: constraints})
+}
+
+// ListGenerators returns the names of every generator registered,
+// regardless of platform, sorted alphabetically. Intended for tooling and
+// doc generation.
+func (r *Registry) ListGenerators() []string {
+	names := make([]string, len(r.entries))
+	for i, entry := range r.entries {
+		names[i] = entry.name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build instantiates one Generator for each entry whose constraints match
+// running.
+func (r *Registry) Build(running BuildConstraints) []Generator {
+	var built []Generator
+	for _, entry := range r.entries {
+		if entry.constraints.Matches(running) {
+			built = append(built, entry.factory())
+		}
+	}
+	return built
+}
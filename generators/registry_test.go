@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yext/edward/generators/memfs"
+)
+
+type fakeGenerator struct {
+	generatorBase
+	name string
+}
+
+func (g *fakeGenerator) Name() string { return g.name }
+
+func (g *fakeGenerator) VisitDir(ctx context.Context, path string, fs FileSystem) (bool, error) {
+	return false, nil
+}
+
+func TestGeneratorCollection_GeneratorsBuildsFromRegistry(t *testing.T) {
+	registry := &Registry{}
+	registry.Register("from-registry", func() Generator { return &fakeGenerator{name: "from-registry"} }, BuildConstraints{})
+
+	gc := &GeneratorCollection{Registry: registry}
+
+	names := generatorNames(gc.generators())
+	if len(names) != 1 || names[0] != "from-registry" {
+		t.Fatalf("expected the Registry's generator to be built, got %v", names)
+	}
+}
+
+func TestGeneratorCollection_GenerateDoesNotDoubleRegisterOnRepeatedCalls(t *testing.T) {
+	registry := &Registry{}
+	registry.Register("from-registry", func() Generator { return &fakeGenerator{name: "from-registry"} }, BuildConstraints{})
+
+	gc := &GeneratorCollection{
+		Registry: registry,
+		Path:     ".",
+		FS:       memfs.New(),
+	}
+
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("first Generate: %v", err)
+	}
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("second Generate: %v", err)
+	}
+
+	names := generatorNames(gc.generators())
+	if len(names) != 1 {
+		t.Fatalf("expected a single registry-built generator after two Generate calls, got %v", names)
+	}
+}
+
+func TestGeneratorCollection_GeneratorsSkipsConstraintMismatch(t *testing.T) {
+	registry := &Registry{}
+	registry.Register("never", func() Generator { return &fakeGenerator{name: "never"} }, BuildConstraints{GOOS: "plan9"})
+
+	gc := &GeneratorCollection{
+		Registry: registry,
+		Context:  BuildConstraints{GOOS: "linux", GOARCH: "amd64"},
+	}
+
+	if names := generatorNames(gc.generators()); len(names) != 0 {
+		t.Fatalf("expected no generators to match a GOOS constraint for a different platform, got %v", names)
+	}
+}
+
+func generatorNames(generators []Generator) []string {
+	var names []string
+	for _, generator := range generators {
+		names = append(names, generator.Name())
+	}
+	return names
+}
@@ -0,0 +1,132 @@
+package generators
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DirState is one generator's recorded result for a single directory: the
+// fingerprint of that directory's contents at the time it was visited,
+// plus the outcome of the visit.
+type DirState struct {
+	ModTime     time.Time
+	ListingSize int
+	Err         string
+}
+
+// unchanged reports whether other describes the same directory contents
+// as s, and that s's visit didn't error.
+func (s DirState) unchanged(other DirState) bool {
+	return s.Err == "" && s.ModTime.Equal(other.ModTime) && s.ListingSize == other.ListingSize
+}
+
+// WalkState is the full persisted result of a GeneratorCollection walk:
+// each generator's DirState for every directory it visited, keyed first by
+// generator name and then by directory path.
+type WalkState struct {
+	Dirs map[string]map[string]DirState
+}
+
+// StateStore persists a WalkState between runs of a GeneratorCollection,
+// so a walk interrupted or re-run over a large tree can replay Resumable
+// generators on directories whose fingerprint hasn't changed instead of
+// visiting them again. Every directory is still walked and fingerprinted.
+type StateStore interface {
+	Load() (*WalkState, error)
+	Save(*WalkState) error
+}
+
+// Resumable is implemented by generators that can replay a previous
+// result instead of being visited again, when StateStore reports that a
+// directory's fingerprint hasn't changed since the last walk.
+type Resumable interface {
+	Generator
+
+	// Replay re-adds path's previously discovered results (as returned by
+	// the ServiceGenerator/GroupGenerator/ImportGenerator accessors) without
+	// re-reading path from the filesystem.
+	Replay(path string, state DirState) error
+}
+
+// walkTracker threads per-directory fingerprinting through a walk. It
+// records every generator's DirState for every directory visited into
+// current, and -- if a previous WalkState was loaded -- lets visit skip
+// directories whose fingerprint hasn't changed.
+type walkTracker struct {
+	filesystem FileSystem
+	previous   *WalkState
+	current    *WalkState
+
+	mu sync.Mutex
+}
+
+func newWalkTracker(filesystem FileSystem, previous *WalkState) *walkTracker {
+	return &walkTracker{
+		filesystem: filesystem,
+		previous:   previous,
+		current:    &WalkState{Dirs: make(map[string]map[string]DirState)},
+	}
+}
+
+// fingerprint computes path's current DirState, based on its listing size
+// and modification time. It does not set Err.
+func (t *walkTracker) fingerprint(path string) (DirState, error) {
+	info, err := t.filesystem.Stat(path)
+	if err != nil {
+		return DirState{}, errors.WithStack(err)
+	}
+
+	listing, err := t.filesystem.ReadDir(path)
+	if err != nil {
+		return DirState{}, errors.WithStack(err)
+	}
+
+	return DirState{ModTime: info.ModTime(), ListingSize: len(listing)}, nil
+}
+
+// prior returns generatorName's last recorded DirState for path, and
+// whether it matches current (i.e. the directory can be skipped).
+func (t *walkTracker) prior(generatorName, path string, current DirState) (DirState, bool) {
+	if t.previous == nil {
+		return DirState{}, false
+	}
+	byPath, ok := t.previous.Dirs[generatorName]
+	if !ok {
+		return DirState{}, false
+	}
+	state, ok := byPath[path]
+	if !ok || !state.unchanged(current) {
+		return DirState{}, false
+	}
+	return state, true
+}
+
+func (t *walkTracker) record(generatorName, path string, state DirState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byPath, ok := t.current.Dirs[generatorName]
+	if !ok {
+		byPath = make(map[string]DirState)
+		t.current.Dirs[generatorName] = byPath
+	}
+	byPath[path] = state
+}
+
+// Errors returns the per-directory errors recorded for generatorName
+// during the most recent Generate call, keyed by directory path.
+func (g *GeneratorCollection) Errors(generatorName string) map[string]string {
+	if g.lastState == nil {
+		return nil
+	}
+
+	errs := make(map[string]string)
+	for path, state := range g.lastState.Dirs[generatorName] {
+		if state.Err != "" {
+			errs[path] = state.Err
+		}
+	}
+	return errs
+}
@@ -0,0 +1,89 @@
+package generators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yext/edward/generators/memfs"
+)
+
+type countingGenerator struct {
+	generatorBase
+	name   string
+	visits int
+}
+
+func (g *countingGenerator) Name() string { return g.name }
+
+func (g *countingGenerator) VisitDir(ctx context.Context, path string, fs FileSystem) (bool, error) {
+	g.visits++
+	return false, nil
+}
+
+type resumableGenerator struct {
+	countingGenerator
+	replays int
+}
+
+func (g *resumableGenerator) Replay(path string, state DirState) error {
+	g.replays++
+	return nil
+}
+
+type memStateStore struct {
+	state *WalkState
+}
+
+func (s *memStateStore) Load() (*WalkState, error)  { return s.state, nil }
+func (s *memStateStore) Save(state *WalkState) error { s.state = state; return nil }
+
+func TestGenerate_NonResumableGeneratorAlwaysVisited(t *testing.T) {
+	filesystem := memfs.New().Dir("a")
+	store := &memStateStore{}
+	gen := &countingGenerator{name: "counting"}
+	gc := &GeneratorCollection{
+		Generators: []Generator{gen},
+		Path:       ".",
+		FS:         filesystem,
+		StateStore: store,
+	}
+
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("first Generate: %v", err)
+	}
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("second Generate: %v", err)
+	}
+
+	if gen.visits != 4 {
+		t.Fatalf("expected a non-Resumable generator to be visited on every run (2 dirs x 2 runs), got %d visits", gen.visits)
+	}
+}
+
+func TestGenerate_ResumableGeneratorSkipsUnchangedDirs(t *testing.T) {
+	filesystem := memfs.New().Dir("a")
+	store := &memStateStore{}
+	gen := &resumableGenerator{countingGenerator: countingGenerator{name: "resumable"}}
+	gc := &GeneratorCollection{
+		Generators: []Generator{gen},
+		Path:       ".",
+		FS:         filesystem,
+		StateStore: store,
+	}
+
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("first Generate: %v", err)
+	}
+	firstVisits := gen.visits
+
+	if err := gc.Generate(context.Background()); err != nil {
+		t.Fatalf("second Generate: %v", err)
+	}
+
+	if gen.visits != firstVisits {
+		t.Fatalf("expected no new VisitDir calls once fingerprints match, got %d additional visits", gen.visits-firstVisits)
+	}
+	if gen.replays == 0 {
+		t.Fatal("expected unchanged directories to be replayed")
+	}
+}